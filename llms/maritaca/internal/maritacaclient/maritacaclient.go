@@ -0,0 +1,218 @@
+// Package maritacaclient provides a thin HTTP client for the Maritaca
+// chat-completions API, used internally by llms/maritaca.
+package maritacaclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Options holds the generation parameters accepted by the Maritaca
+// chat-completions endpoint. Zero-valued fields are omitted from the
+// request body via their JSON tags, so only parameters the caller
+// explicitly set are sent.
+type Options struct {
+	ChatMode            bool               `json:"chat_mode,omitempty"`
+	MaxTokens           int                `json:"max_tokens,omitempty"`
+	DoSample            bool               `json:"do_sample,omitempty"`
+	Temperature         float64            `json:"temperature,omitempty"`
+	TopP                float64            `json:"top_p,omitempty"`
+	MinP                float64            `json:"min_p,omitempty"`
+	TypicalP            float64            `json:"typical_p,omitempty"`
+	RepetitionPenalty   float64            `json:"repetition_penalty,omitempty"`
+	FrequencyPenalty    float64            `json:"frequency_penalty,omitempty"`
+	PresencePenalty     float64            `json:"presence_penalty,omitempty"`
+	StoppingTokens      []string           `json:"stopping_tokens,omitempty"`
+	Stream              bool               `json:"stream,omitempty"`
+	NumTokensPerMessage int                `json:"num_tokens_per_message,omitempty"`
+	LogitBias           map[string]float64 `json:"logit_bias,omitempty"`
+
+	// Token is the API key used to authenticate the request. It is sent
+	// in the Authorization header ("Key <token>"), never in the request body.
+	Token string `json:"-"`
+}
+
+// GenerateRequest is the body sent to the Maritaca chat-completions
+// endpoint.
+type GenerateRequest struct {
+	Model    string   `json:"model,omitempty"`
+	Messages []string `json:"messages,omitempty"`
+	Do       string   `json:"do,omitempty"`
+	System   string   `json:"system,omitempty"`
+	Format   string   `json:"format,omitempty"`
+
+	// ResponseSchema and ResponseSchemaStrict are sent to backends that
+	// support schema-guided decoding; older deployments ignore them, so
+	// callers should also rely on the system-prompt preamble plus
+	// post-generation validation for correctness.
+	ResponseSchema       json.RawMessage `json:"response_schema,omitempty"`
+	ResponseSchemaStrict bool            `json:"response_schema_strict,omitempty"`
+
+	Options
+}
+
+// GenerateResponse is the body returned by the Maritaca chat-completions
+// endpoint for a non-streaming request.
+type GenerateResponse struct {
+	Model        string `json:"model"`
+	Answer       string `json:"answer"`
+	FinishReason string `json:"finish_reason"`
+	TokensCount  int    `json:"tokens_count"`
+}
+
+// StreamEvent is a single chunk of a streaming chat-completions response.
+type StreamEvent struct {
+	Model        string             `json:"model"`
+	Text         string             `json:"text"`
+	TokensCount  int                `json:"tokens_count"`
+	FinishReason string             `json:"finish_reason"`
+	LogProbs     map[string]float64 `json:"logprobs,omitempty"`
+}
+
+// Client is a minimal HTTP client for the Maritaca chat-completions API.
+type Client struct {
+	base       *url.URL
+	httpClient *http.Client
+}
+
+// NewClient builds a Client that sends requests to base using
+// httpClient.
+func NewClient(base *url.URL, httpClient *http.Client) (*Client, error) {
+	if base == nil {
+		return nil, fmt.Errorf("maritacaclient: base URL is required")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{base: base, httpClient: httpClient}, nil
+}
+
+// endpoint returns the URL used for the chat-completions request.
+func (c *Client) endpoint() string {
+	u := *c.base
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/chat/inference"
+	return u.String()
+}
+
+// newRequest builds the *http.Request for req, attaching req.Token as a
+// "Key" Authorization header when set.
+func (c *Client) newRequest(ctx context.Context, req *GenerateRequest) (*http.Request, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("maritacaclient: marshaling request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("maritacaclient: building request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if req.Token != "" {
+		httpReq.Header.Set("Authorization", "Key "+req.Token)
+	}
+	return httpReq, nil
+}
+
+// Generate performs a single, non-streaming chat-completions request.
+func (c *Client) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	req.Stream = false
+
+	httpReq, err := c.newRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("maritacaclient: performing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("maritacaclient: unexpected status code %d", resp.StatusCode)
+	}
+
+	var out GenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("maritacaclient: decoding response: %w", err)
+	}
+	return &out, nil
+}
+
+// StreamFunc is called once per event parsed from a streaming response.
+// Returning an error stops reading the stream and aborts the underlying
+// HTTP request via ctx cancellation.
+type StreamFunc func(ctx context.Context, event StreamEvent) error
+
+// GenerateStreaming performs a streaming chat-completions request,
+// invoking fn for each event parsed from the newline-delimited JSON
+// response, and returns the accumulated final response once the stream
+// ends. If fn returns an error, reading stops and that error is returned
+// with no response.
+func (c *Client) GenerateStreaming(ctx context.Context, req *GenerateRequest, fn StreamFunc) (*GenerateResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req.Stream = true
+
+	httpReq, err := c.newRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("maritacaclient: performing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("maritacaclient: unexpected status code %d", resp.StatusCode)
+	}
+
+	var final GenerateResponse
+	var text strings.Builder
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		line = strings.TrimPrefix(line, "data: ")
+
+		var event StreamEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, fmt.Errorf("maritacaclient: decoding stream event: %w", err)
+		}
+
+		text.WriteString(event.Text)
+		final.Model = event.Model
+		final.TokensCount += event.TokensCount
+		if event.FinishReason != "" {
+			final.FinishReason = event.FinishReason
+		}
+
+		if fn != nil {
+			if err := fn(ctx, event); err != nil {
+				cancel()
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("maritacaclient: reading stream: %w", err)
+	}
+
+	final.Answer = text.String()
+	return &final, nil
+}
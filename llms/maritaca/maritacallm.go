@@ -0,0 +1,214 @@
+package maritaca
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/silverfox07/langchaingo/callbacks"
+	"github.com/silverfox07/langchaingo/llms"
+	"github.com/silverfox07/langchaingo/llms/maritaca/internal/maritacaclient"
+)
+
+// ErrEmptyResponse is returned when the Maritaca backend returns no
+// generated text.
+var ErrEmptyResponse = errors.New("maritaca: empty response from model")
+
+const defaultMaritacaServerURL = "https://chat.maritaca.ai/api"
+
+// LLM is a langchaingo llms.Model backed by a Maritaca chat-completions
+// endpoint.
+type LLM struct {
+	CallbacksHandler callbacks.Handler
+	client           *maritacaclient.Client
+	options          options
+}
+
+var _ llms.Model = (*LLM)(nil)
+
+// New creates a new Maritaca LLM from the given options.
+func New(opts ...Option) (*LLM, error) {
+	o := options{
+		httpClient: http.DefaultClient,
+		// -1 means WithStructuredRetries was never called; enforceStructuredOutput
+		// falls back to defaultStructuredRetries in that case, but leaves an
+		// explicit WithStructuredRetries(0) ("no repair attempts") alone.
+		structuredRetries: -1,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.err != nil {
+		return nil, o.err
+	}
+
+	if o.maritacaServerURL == nil {
+		var err error
+		o.maritacaServerURL, err = url.Parse(defaultMaritacaServerURL)
+		if err != nil {
+			return nil, fmt.Errorf("maritaca: parsing default server URL: %w", err)
+		}
+	}
+
+	httpClient := o.httpClient
+	if len(o.endpoints) > 0 {
+		routed := *httpClient
+		routed.Transport = newRoutingTransport(o.endpoints, o.routingPolicy, o.routingObserver, httpClient.Transport)
+		httpClient = &routed
+	}
+
+	client, err := maritacaclient.NewClient(o.maritacaServerURL, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LLM{client: client, options: o}, nil
+}
+
+// Call requests a completion for a single prompt. It is a convenience
+// wrapper around GenerateContent for callers that don't need multi-turn
+// messages.
+func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, o, prompt, options...)
+}
+
+// GenerateContent implements llms.Model.
+func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	callOpts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&callOpts)
+	}
+
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
+	req := o.buildRequest(messages, callOpts)
+
+	streamingFunc := o.streamingFunc(callOpts)
+
+	var resp *maritacaclient.GenerateResponse
+	var err error
+	if req.Stream || streamingFunc != nil {
+		// With no user callback, the chunks are simply accumulated by
+		// GenerateStreaming and returned as a single response, preserving
+		// WithStream(true)'s pre-callback behavior.
+		resp, err = o.client.GenerateStreaming(ctx, req, func(ctx context.Context, event maritacaclient.StreamEvent) error {
+			if streamingFunc == nil {
+				return nil
+			}
+			return streamingFunc(ctx, StreamChunk{
+				Content:      event.Text,
+				TokenCount:   event.TokensCount,
+				FinishReason: event.FinishReason,
+				LogProbs:     event.LogProbs,
+			})
+		})
+	} else {
+		resp, err = o.client.Generate(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.Answer == "" {
+		return nil, ErrEmptyResponse
+	}
+
+	answer := resp.Answer
+	generationInfo := map[string]any{"finish_reason": resp.FinishReason}
+
+	if o.options.responseFormat != nil {
+		validated, value, err := o.enforceStructuredOutput(ctx, req, answer)
+		if err != nil {
+			return nil, err
+		}
+		answer = validated
+		generationInfo["structured_output"] = value
+	}
+
+	contentResp := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{
+			{
+				Content:        answer,
+				StopReason:     resp.FinishReason,
+				GenerationInfo: generationInfo,
+			},
+		},
+	}
+
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, contentResp)
+	}
+	return contentResp, nil
+}
+
+// buildRequest translates messages and per-call options into a
+// maritacaclient.GenerateRequest, applying the LLM's configured options
+// (model, sampling parameters, logit bias, and so on).
+func (o *LLM) buildRequest(messages []llms.MessageContent, callOpts llms.CallOptions) *maritacaclient.GenerateRequest {
+	prompts := make([]string, 0, len(messages))
+	for _, m := range messages {
+		for _, part := range m.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				prompts = append(prompts, text.Text)
+			}
+		}
+	}
+
+	maritacaOpts := o.options.maritacaOptions
+	if callOpts.Temperature > 0 {
+		maritacaOpts.Temperature = callOpts.Temperature
+	}
+	if callOpts.TopP > 0 {
+		maritacaOpts.TopP = callOpts.TopP
+	}
+	if callOpts.MaxTokens > 0 {
+		maritacaOpts.MaxTokens = callOpts.MaxTokens
+	}
+	if len(callOpts.StopWords) > 0 {
+		maritacaOpts.StoppingTokens = callOpts.StopWords
+	}
+
+	applyPenaltyFallback(&maritacaOpts)
+
+	model := o.options.model
+	if callOpts.Model != "" {
+		model = callOpts.Model
+	}
+
+	system := o.options.system
+	req := &maritacaclient.GenerateRequest{
+		Model:    model,
+		Messages: prompts,
+		System:   system,
+		Format:   o.options.format,
+		Options:  maritacaOpts,
+	}
+
+	if format := o.options.responseFormat; format != nil {
+		// Ask any backend with schema-guided decoding to honor the schema
+		// directly...
+		req.ResponseSchema = format.Schema
+		req.ResponseSchemaStrict = format.Strict
+		// ...and fall back to a system-prompt preamble plus
+		// validate-and-repair for backends that don't.
+		req.System = joinNonEmpty(system, structuredOutputPreamble(format))
+	}
+
+	return req
+}
+
+// joinNonEmpty joins a and b with a blank line, skipping either side when
+// empty.
+func joinNonEmpty(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + "\n\n" + b
+	}
+}
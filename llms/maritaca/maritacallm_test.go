@@ -0,0 +1,99 @@
+package maritaca
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/silverfox07/langchaingo/llms"
+)
+
+func newTestServer(t *testing.T, streaming bool) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if streaming {
+			fmt.Fprintln(w, `{"model":"sabia-3","text":"hel","tokens_count":1}`)
+			fmt.Fprintln(w, `{"model":"sabia-3","text":"lo","tokens_count":1,"finish_reason":"stop"}`)
+			return
+		}
+		fmt.Fprintln(w, `{"model":"sabia-3","answer":"hello","finish_reason":"stop"}`)
+	}))
+}
+
+func TestGenerateContentWithStreamButNoCallbackAccumulatesChunks(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, true)
+	defer server.Close()
+
+	llm, err := New(WithServerURL(server.URL), WithStream(true))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "hi"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateContent: %v", err)
+	}
+
+	if got := resp.Choices[0].Content; got != "hello" {
+		t.Errorf("expected accumulated content %q, got %q", "hello", got)
+	}
+}
+
+func TestGenerateContentWithStreamingFuncReceivesChunks(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, true)
+	defer server.Close()
+
+	var chunks []string
+	llm, err := New(WithServerURL(server.URL), WithStreamingFunc(func(_ context.Context, chunk StreamChunk) error {
+		chunks = append(chunks, chunk.Content)
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "hi"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateContent: %v", err)
+	}
+
+	if got := strings.Join(chunks, ""); got != "hello" {
+		t.Errorf("expected streamed chunks to join to %q, got %q", "hello", got)
+	}
+	if resp.Choices[0].Content != "hello" {
+		t.Errorf("expected final content %q, got %q", "hello", resp.Choices[0].Content)
+	}
+}
+
+func TestGenerateContentNonStreaming(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, false)
+	defer server.Close()
+
+	llm, err := New(WithServerURL(server.URL))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "hi"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateContent: %v", err)
+	}
+	if resp.Choices[0].Content != "hello" {
+		t.Errorf("expected content %q, got %q", "hello", resp.Choices[0].Content)
+	}
+}
@@ -1,6 +1,7 @@
 package maritaca
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
@@ -8,6 +9,22 @@ import (
 	"github.com/silverfox07/langchaingo/llms/maritaca/internal/maritacaclient"
 )
 
+// logitBiasMin and logitBiasMax bound the bias that can be applied to a
+// single token, matching the range used by the OpenAI and Roli
+// chat-completions APIs.
+const (
+	logitBiasMin = -100
+	logitBiasMax = 100
+)
+
+// penaltyMin and penaltyMax bound the additive frequency/presence
+// penalties, matching the range used by the OpenAI, Vercel AI SDK, and
+// Writer APIs.
+const (
+	penaltyMin = -2.0
+	penaltyMax = 2.0
+)
+
 type options struct {
 	maritacaServerURL   *url.URL
 	httpClient          *http.Client
@@ -16,6 +33,23 @@ type options struct {
 	customModelTemplate string
 	system              string
 	format              string
+	responseFormat      *ResponseFormat
+	structuredRetries   int
+	streamingFunc       StreamingFunc
+	endpoints           []EndpointSpec
+	routingPolicy       Policy
+	routingObserver     Observer
+	err                 error
+}
+
+// addErr records a validation error raised while applying an Option,
+// instead of terminating the process. New returns the first recorded
+// error, if any, so callers embedded in long-running servers can handle
+// bad configuration themselves.
+func addErr(opts *options, err error) {
+	if opts.err == nil {
+		opts.err = err
+	}
 }
 
 type Option func(*options)
@@ -126,6 +160,41 @@ func WithTopP(topP float64) Option {
 	}
 }
 
+// WithMinP Set the minimum probability threshold for min-p sampling.
+// minimum: 0
+// maximum: 1
+// Filters out tokens whose probability is lower than min_p * p_max, where
+// p_max is the probability of the most likely token. For example, with
+// min_p=0.05 and a top token probability of 0.9, only tokens with
+// probability >= 0.045 are considered. Unset (zero) means the field is
+// omitted from the request. See https://github.com/ggerganov/llama.cpp.
+func WithMinP(minP float64) Option {
+	return func(opts *options) {
+		if minP < 0 || minP > 1 {
+			addErr(opts, fmt.Errorf("maritaca: min_p must be in [0, 1], got %v", minP))
+			return
+		}
+		opts.maritacaOptions.MinP = minP
+	}
+}
+
+// WithTypicalP Set the typical-p (locally typical sampling) threshold.
+// minimum: 0
+// maximum: 1
+// Keeps the smallest set of tokens whose information content is closest
+// to the distribution's conditional entropy, accumulating probability
+// mass up to typical_p. Can be combined with WithTopP; unset (zero)
+// means the field is omitted from the request.
+func WithTypicalP(typicalP float64) Option {
+	return func(opts *options) {
+		if typicalP < 0 || typicalP > 1 {
+			addErr(opts, fmt.Errorf("maritaca: typical_p must be in [0, 1], got %v", typicalP))
+			return
+		}
+		opts.maritacaOptions.TypicalP = typicalP
+	}
+}
+
 // WithFrequencyPenalty Set the frequency penalty.
 //
 //	minimum: 0
@@ -146,6 +215,47 @@ func WithStoppingTokens(tokens []string) Option {
 	}
 }
 
+// WithFrequencyPenalty Set the frequency penalty.
+// minimum: -2
+// maximum: 2
+// default: 0
+// Additive penalty, roughly in -2.0..2.0, applied based on how frequently
+// a token has already appeared in the generated text so far. Positive
+// values discourage repetition; negative values encourage it. If the
+// Maritaca backend does not support frequency_penalty natively, it is
+// combined with WithPresencePenalty into WithRepetitionPenalty's
+// multiplicative factor (see penaltiesToRepetitionPenalty).
+func WithFrequencyPenalty(frequencyPenalty float64) Option {
+	return func(opts *options) {
+		if frequencyPenalty < penaltyMin || frequencyPenalty > penaltyMax {
+			addErr(opts, fmt.Errorf("maritaca: frequency penalty must be in [%v, %v], got %v", penaltyMin, penaltyMax, frequencyPenalty))
+			return
+		}
+		opts.maritacaOptions.FrequencyPenalty = frequencyPenalty
+	}
+}
+
+// WithPresencePenalty Set the presence penalty.
+// minimum: -2
+// maximum: 2
+// default: 0
+// Additive penalty, roughly in -2.0..2.0, applied to any token that has
+// appeared in the generated text at least once, regardless of how many
+// times. Positive values discourage reusing tokens; negative values
+// encourage it. If the Maritaca backend does not support
+// presence_penalty natively, it is combined with WithFrequencyPenalty
+// into WithRepetitionPenalty's multiplicative factor (see
+// penaltiesToRepetitionPenalty).
+func WithPresencePenalty(presencePenalty float64) Option {
+	return func(opts *options) {
+		if presencePenalty < penaltyMin || presencePenalty > penaltyMax {
+			addErr(opts, fmt.Errorf("maritaca: presence penalty must be in [%v, %v], got %v", penaltyMin, penaltyMax, presencePenalty))
+			return
+		}
+		opts.maritacaOptions.PresencePenalty = presencePenalty
+	}
+}
+
 // WithStream Set the model will run in streaming mode.
 // default: false
 // If True, the model will run in streaming mode,
@@ -176,3 +286,20 @@ func WithToken(token string) Option {
 		opts.maritacaOptions.Token = token
 	}
 }
+
+// WithLogitBias sets a per-token bias to apply to the logits before
+// sampling. Keys are the exact token IDs of the model's tokenizer
+// (as a string), and values are the bias to apply, roughly in the
+// range -100..100: values near ±1 gently discourage/encourage a
+// token, while ±100 effectively bans or forces it.
+func WithLogitBias(logitBias map[string]float64) Option {
+	return func(opts *options) {
+		for token, bias := range logitBias {
+			if bias < logitBiasMin || bias > logitBiasMax {
+				addErr(opts, fmt.Errorf("maritaca: logit bias %v for token %q must be in [%v, %v]", bias, token, logitBiasMin, logitBiasMax))
+				return
+			}
+		}
+		opts.maritacaOptions.LogitBias = logitBias
+	}
+}
@@ -0,0 +1,99 @@
+package maritaca
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWithMinPAndTypicalPOnlyMarshalFieldsThatAreSet(t *testing.T) {
+	t.Parallel()
+
+	var o options
+	for _, opt := range []Option{WithMinP(0.05), WithModel("sabia-3")} {
+		opt(&o)
+	}
+	if o.err != nil {
+		t.Fatalf("unexpected error: %v", o.err)
+	}
+
+	payload, err := json.Marshal(o.maritacaOptions)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if _, ok := fields["min_p"]; !ok {
+		t.Errorf("expected min_p to be present in payload, got %s", payload)
+	}
+	if _, ok := fields["typical_p"]; ok {
+		t.Errorf("expected typical_p to be omitted when unset, got %s", payload)
+	}
+	if _, ok := fields["top_p"]; ok {
+		t.Errorf("expected top_p to be omitted when unset, got %s", payload)
+	}
+}
+
+func TestWithMinPRejectsOutOfRangeValues(t *testing.T) {
+	t.Parallel()
+
+	var o options
+	WithMinP(1.5)(&o)
+
+	if o.err == nil {
+		t.Fatal("expected an error for an out-of-range min_p, got nil")
+	}
+}
+
+func TestWithTypicalPRejectsOutOfRangeValues(t *testing.T) {
+	t.Parallel()
+
+	var o options
+	WithTypicalP(-0.1)(&o)
+
+	if o.err == nil {
+		t.Fatal("expected an error for an out-of-range typical_p, got nil")
+	}
+}
+
+func TestWithLogitBiasMarshalsSetFields(t *testing.T) {
+	t.Parallel()
+
+	var o options
+	WithLogitBias(map[string]float64{"123": 50, "456": -10})(&o)
+	if o.err != nil {
+		t.Fatalf("unexpected error: %v", o.err)
+	}
+
+	payload, err := json.Marshal(o.maritacaOptions)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	biases, ok := fields["logit_bias"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected logit_bias to be present in payload, got %s", payload)
+	}
+	if biases["123"] != float64(50) || biases["456"] != float64(-10) {
+		t.Errorf("expected logit_bias values to be forwarded unmodified, got %v", biases)
+	}
+}
+
+func TestWithLogitBiasRejectsOutOfRangeValues(t *testing.T) {
+	t.Parallel()
+
+	var o options
+	WithLogitBias(map[string]float64{"123": 150})(&o)
+
+	if o.err == nil {
+		t.Fatal("expected an error for an out-of-range logit bias, got nil")
+	}
+}
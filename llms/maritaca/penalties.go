@@ -0,0 +1,38 @@
+package maritaca
+
+import "github.com/silverfox07/langchaingo/llms/maritaca/internal/maritacaclient"
+
+// applyPenaltyFallback fills in RepetitionPenalty from the additive
+// presence/frequency penalties when the caller set one of them but did
+// not explicitly configure WithRepetitionPenalty. The native
+// frequency_penalty/presence_penalty fields are always sent unmodified
+// alongside it, so Maritaca deployments with native support use those
+// directly and ignore the derived repetition_penalty, while older
+// deployments fall back to it.
+func applyPenaltyFallback(o *maritacaclient.Options) {
+	if o.RepetitionPenalty != 0 {
+		return
+	}
+	if o.PresencePenalty == 0 && o.FrequencyPenalty == 0 {
+		return
+	}
+	o.RepetitionPenalty = penaltiesToRepetitionPenalty(o.PresencePenalty, o.FrequencyPenalty)
+}
+
+// penaltiesToRepetitionPenalty translates the additive presence/frequency
+// penalties onto the Maritaca backend's multiplicative repetition_penalty
+// factor, for deployments that have not yet added native support for
+// presence_penalty/frequency_penalty. It uses rep = 1 + max(presence,
+// frequency), clamped to [0, 2], so that a zero penalty on both axes
+// reproduces the backend's neutral repetition_penalty of 1.
+func penaltiesToRepetitionPenalty(presencePenalty, frequencyPenalty float64) float64 {
+	rep := 1 + max(presencePenalty, frequencyPenalty)
+	switch {
+	case rep < 0:
+		return 0
+	case rep > 2:
+		return 2
+	default:
+		return rep
+	}
+}
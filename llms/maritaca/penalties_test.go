@@ -0,0 +1,57 @@
+package maritaca
+
+import (
+	"testing"
+
+	"github.com/silverfox07/langchaingo/llms/maritaca/internal/maritacaclient"
+)
+
+func TestPenaltiesToRepetitionPenalty(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name              string
+		presence          float64
+		frequency         float64
+		wantRepetitionPen float64
+	}{
+		{"both zero", 0, 0, 1},
+		{"presence only", 0.5, 0, 1.5},
+		{"frequency only", 0, 1.2, 2},
+		{"clamped at max", 2, 2, 2},
+		{"clamped at min", -2, -2, 0},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			got := penaltiesToRepetitionPenalty(c.presence, c.frequency)
+			if got != c.wantRepetitionPen {
+				t.Errorf("penaltiesToRepetitionPenalty(%v, %v) = %v, want %v", c.presence, c.frequency, got, c.wantRepetitionPen)
+			}
+		})
+	}
+}
+
+func TestApplyPenaltyFallbackLeavesExplicitRepetitionPenaltyAlone(t *testing.T) {
+	t.Parallel()
+
+	o := maritacaclient.Options{RepetitionPenalty: 1.3, PresencePenalty: 2}
+	applyPenaltyFallback(&o)
+
+	if o.RepetitionPenalty != 1.3 {
+		t.Errorf("expected explicit repetition_penalty to be preserved, got %v", o.RepetitionPenalty)
+	}
+}
+
+func TestApplyPenaltyFallbackDerivesFromPenalties(t *testing.T) {
+	t.Parallel()
+
+	o := maritacaclient.Options{PresencePenalty: 0.4}
+	applyPenaltyFallback(&o)
+
+	if o.RepetitionPenalty != 1.4 {
+		t.Errorf("expected derived repetition_penalty 1.4, got %v", o.RepetitionPenalty)
+	}
+}
@@ -0,0 +1,160 @@
+package maritaca
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/silverfox07/langchaingo/llms/maritaca/internal/maritacaclient"
+)
+
+// defaultStructuredRetries is the number of repair attempts made when the
+// model's output fails schema validation, used when WithStructuredRetries
+// is not set.
+const defaultStructuredRetries = 2
+
+// ResponseFormat describes a JSON schema the model's output must conform
+// to, used by WithResponseFormatJSONSchema.
+type ResponseFormat struct {
+	// Name identifies the schema, mirroring OpenAI's json_schema response
+	// format.
+	Name string
+	// Schema is the JSON Schema document the output must validate against.
+	Schema json.RawMessage
+	// Strict requests schema-guided decoding from backends that support
+	// it, rather than relying solely on post-generation validation.
+	Strict bool
+}
+
+// WithResponseSchema sets the expected shape of the model's output by
+// marshaling schema to JSON and enabling structured output mode. schema
+// may be a json.RawMessage, a map[string]any, or any value that marshals
+// to a valid JSON Schema document.
+func WithResponseSchema(schema any) Option {
+	return func(opts *options) {
+		raw, err := json.Marshal(schema)
+		if err != nil {
+			addErr(opts, fmt.Errorf("maritaca: invalid response schema: %w", err))
+			return
+		}
+		opts.responseFormat = &ResponseFormat{Name: "response", Schema: raw}
+		opts.format = "json"
+	}
+}
+
+// WithResponseFormatJSONSchema enables structured output mode with a named
+// JSON schema. If the Maritaca backend supports schema-guided decoding the
+// schema is sent with the request; otherwise the client falls back to
+// injecting a system-prompt preamble describing the schema and validating
+// (and, if necessary, repairing) the generated output.
+func WithResponseFormatJSONSchema(name string, schema json.RawMessage, strict bool) Option {
+	return func(opts *options) {
+		opts.responseFormat = &ResponseFormat{Name: name, Schema: schema, Strict: strict}
+		opts.format = "json"
+	}
+}
+
+// WithStructuredRetries sets how many times the client will re-prompt the
+// model with a repair prompt (containing the validation errors) after the
+// output fails to validate against the configured response schema. Zero
+// means the first invalid response is returned as an error with no
+// repair attempt.
+// default: 2
+func WithStructuredRetries(n int) Option {
+	return func(opts *options) {
+		opts.structuredRetries = n
+	}
+}
+
+// validateStructuredOutput parses raw as JSON and validates it against
+// format's schema, returning the decoded object on success. The returned
+// error wraps the schema validation errors verbatim so callers can embed
+// them in a repair prompt.
+func validateStructuredOutput(format *ResponseFormat, raw string) (any, error) {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(format.Name, bytes.NewReader(format.Schema)); err != nil {
+		return nil, fmt.Errorf("maritaca: invalid response schema %q: %w", format.Name, err)
+	}
+
+	schema, err := compiler.Compile(format.Name)
+	if err != nil {
+		return nil, fmt.Errorf("maritaca: compiling response schema %q: %w", format.Name, err)
+	}
+
+	var doc any
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, fmt.Errorf("maritaca: output is not valid JSON: %w", err)
+	}
+
+	if err := schema.Validate(doc); err != nil {
+		return nil, fmt.Errorf("maritaca: output does not match schema %q: %w", format.Name, err)
+	}
+
+	return doc, nil
+}
+
+// structuredOutputPreamble builds a system-prompt addendum instructing the
+// model to respond with JSON matching format's schema, for backends that
+// do not support schema-guided decoding natively.
+func structuredOutputPreamble(format *ResponseFormat) string {
+	return fmt.Sprintf(
+		"Respond with a single JSON object that strictly matches the following JSON Schema (name: %s). "+
+			"Do not include any text outside the JSON object.\n%s",
+		format.Name, string(format.Schema),
+	)
+}
+
+// structuredOutputRepairPrompt builds a repair prompt asking the model to
+// fix its previous output given the validation errors it produced.
+func structuredOutputRepairPrompt(format *ResponseFormat, previousOutput string, validationErr error) string {
+	return fmt.Sprintf(
+		"Your previous response did not match the required JSON Schema (name: %s).\n"+
+			"Previous response:\n%s\n\nValidation errors:\n%s\n\n"+
+			"Respond again with a single corrected JSON object that strictly matches the schema.",
+		format.Name, previousOutput, validationErr,
+	)
+}
+
+// ErrStructuredRetriesExhausted is returned by enforceStructuredOutput
+// when the model's output still fails schema validation after
+// exhausting the configured retry budget.
+var ErrStructuredRetriesExhausted = fmt.Errorf("maritaca: exhausted structured-output retries without a valid response")
+
+// enforceStructuredOutput validates text against o.options.responseFormat
+// and, on failure, re-prompts the model with a repair prompt up to
+// o.options.structuredRetries times, returning the last valid text and
+// its parsed value, or ErrStructuredRetriesExhausted if the budget runs
+// out.
+func (o *LLM) enforceStructuredOutput(ctx context.Context, req *maritacaclient.GenerateRequest, text string) (string, any, error) {
+	format := o.options.responseFormat
+
+	retries := o.options.structuredRetries
+	if retries < 0 {
+		retries = defaultStructuredRetries
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		value, err := validateStructuredOutput(format, text)
+		if err == nil {
+			return text, value, nil
+		}
+		lastErr = err
+
+		if attempt >= retries {
+			return "", nil, fmt.Errorf("%w: %v", ErrStructuredRetriesExhausted, lastErr)
+		}
+
+		repairReq := *req
+		repairReq.Messages = append(append([]string{}, req.Messages...), structuredOutputRepairPrompt(format, text, lastErr))
+
+		resp, err := o.client.Generate(ctx, &repairReq)
+		if err != nil {
+			return "", nil, fmt.Errorf("maritaca: structured-output repair request: %w", err)
+		}
+		text = resp.Answer
+	}
+}
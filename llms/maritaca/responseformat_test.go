@@ -0,0 +1,90 @@
+package maritaca
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/silverfox07/langchaingo/llms"
+)
+
+const testSchema = `{"type":"object","required":["answer"],"properties":{"answer":{"type":"string"}}}`
+
+func TestWithStructuredRetriesZeroMeansNoRepairAttempt(t *testing.T) {
+	t.Parallel()
+
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Write([]byte(`{"model":"sabia-3","answer":"not json"}`))
+	}))
+	defer server.Close()
+
+	llm, err := New(
+		WithServerURL(server.URL),
+		WithResponseSchema(json.RawMessage(testSchema)),
+		WithStructuredRetries(0),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "hi"),
+	})
+	if !errors.Is(err, ErrStructuredRetriesExhausted) {
+		t.Fatalf("expected ErrStructuredRetriesExhausted, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected exactly one attempt with WithStructuredRetries(0), got %d", attempts)
+	}
+}
+
+func TestWithStructuredRetriesDefaultsWhenUnset(t *testing.T) {
+	t.Parallel()
+
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Write([]byte(`{"model":"sabia-3","answer":"not json"}`))
+	}))
+	defer server.Close()
+
+	llm, err := New(
+		WithServerURL(server.URL),
+		WithResponseSchema(json.RawMessage(testSchema)),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "hi"),
+	})
+	if !errors.Is(err, ErrStructuredRetriesExhausted) {
+		t.Fatalf("expected ErrStructuredRetriesExhausted, got %v", err)
+	}
+	if attempts != defaultStructuredRetries+1 {
+		t.Errorf("expected %d attempts (default retries), got %d", defaultStructuredRetries+1, attempts)
+	}
+}
+
+func TestBuildRequestSendsSchemaAndStrictToBackend(t *testing.T) {
+	t.Parallel()
+
+	llm := &LLM{options: options{
+		responseFormat: &ResponseFormat{Name: "answer", Schema: json.RawMessage(testSchema), Strict: true},
+	}}
+
+	req := llm.buildRequest(nil, llms.CallOptions{})
+
+	if string(req.ResponseSchema) != testSchema {
+		t.Errorf("expected ResponseSchema to be forwarded, got %s", req.ResponseSchema)
+	}
+	if !req.ResponseSchemaStrict {
+		t.Error("expected ResponseSchemaStrict to be true")
+	}
+}
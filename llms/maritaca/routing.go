@@ -0,0 +1,299 @@
+package maritaca
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrNoEndpointsAvailable is returned when every configured endpoint has
+// been exhausted (or none support the request's parameters) without a
+// successful response.
+var ErrNoEndpointsAvailable = errors.New("maritaca: no endpoint available to serve the request")
+
+// EndpointSpec describes a single upstream Maritaca-compatible server
+// that the routing layer can dispatch requests to.
+type EndpointSpec struct {
+	// Name identifies the endpoint in metrics and error messages.
+	Name string
+	// BaseURL is the root URL of the endpoint, e.g. "https://host". Only
+	// its scheme and host are used; any path in it is ignored.
+	BaseURL string
+	// CostWeight is used by PolicyCheapestFirst to order endpoints;
+	// lower is cheaper.
+	CostWeight float64
+	// SupportedParameters lists the request parameters (e.g.
+	// "logit_bias", "min_p") this endpoint understands. An empty slice
+	// means "supports everything", so require-parameters never skips it.
+	SupportedParameters []string
+}
+
+// supports reports whether the endpoint advertises support for every
+// parameter in params.
+func (e EndpointSpec) supports(params []string) bool {
+	if len(e.SupportedParameters) == 0 {
+		return true
+	}
+	supported := make(map[string]bool, len(e.SupportedParameters))
+	for _, p := range e.SupportedParameters {
+		supported[p] = true
+	}
+	for _, p := range params {
+		if !supported[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// PolicyKind selects how the routing transport orders and skips
+// endpoints.
+type PolicyKind int
+
+const (
+	// PolicyOrderedFallback tries endpoints in the order they were
+	// registered, falling through to the next one on 5xx responses,
+	// timeouts, or 429s.
+	PolicyOrderedFallback PolicyKind = iota
+	// PolicyCheapestFirst tries endpoints ordered by ascending
+	// CostWeight.
+	PolicyCheapestFirst
+	// PolicyRequireParameters skips endpoints that do not advertise
+	// support for every parameter listed in Policy.RequiredParameters,
+	// then applies ordered-fallback among the remaining endpoints. The
+	// parameters are not read from the outgoing request.
+	PolicyRequireParameters
+)
+
+// Policy configures the routing transport's endpoint selection.
+type Policy struct {
+	Kind PolicyKind
+	// MaxAttempts bounds how many endpoints are tried before giving up.
+	// default: len(endpoints)
+	MaxAttempts int
+	// RequiredParameters is consulted when Kind is
+	// PolicyRequireParameters.
+	RequiredParameters []string
+}
+
+// AttemptResult summarizes a single attempt against one endpoint, passed
+// to Observer.OnAttempt.
+type AttemptResult struct {
+	Endpoint   EndpointSpec
+	Attempt    int
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+}
+
+// Observer receives per-attempt metrics from the routing transport. Both
+// methods must be safe to call concurrently.
+type Observer interface {
+	// OnAttempt is called after every attempt, successful or not.
+	OnAttempt(result AttemptResult)
+	// OnExhausted is called once the transport stops trying endpoints
+	// without success, after at most Policy.MaxAttempts of the
+	// policy-eligible endpoints. It is not called when the request's
+	// context is cancelled between attempts.
+	OnExhausted(lastErr error)
+}
+
+// noopObserver is used when WithEndpoints is set without an explicit
+// Observer.
+type noopObserver struct{}
+
+func (noopObserver) OnAttempt(AttemptResult) {}
+func (noopObserver) OnExhausted(error)       {}
+
+// WithEndpoints registers the upstream endpoints the routing transport
+// may dispatch requests to. When set, requests go only to these
+// endpoints: each attempt takes the scheme and host of an endpoint's
+// BaseURL and keeps the path built from WithServerURL (or the default
+// server URL). Passing a single endpoint disables fallback/cost
+// ordering but still goes through the routing transport.
+func WithEndpoints(endpoints []EndpointSpec) Option {
+	return func(opts *options) {
+		opts.endpoints = endpoints
+	}
+}
+
+// WithRoutingPolicy sets the policy used to order and skip endpoints
+// registered via WithEndpoints.
+// default: PolicyOrderedFallback
+func WithRoutingPolicy(policy Policy) Option {
+	return func(opts *options) {
+		opts.routingPolicy = policy
+	}
+}
+
+// WithRoutingObserver sets the Observer notified of each routing attempt.
+// default: a no-op observer
+func WithRoutingObserver(observer Observer) Option {
+	return func(opts *options) {
+		opts.routingObserver = observer
+	}
+}
+
+// routingTransport is an http.RoundTripper that retries a request across
+// a set of EndpointSpecs according to a Policy, with exponential backoff
+// and jitter between attempts. Retries honor the request's context.
+type routingTransport struct {
+	endpoints []EndpointSpec
+	policy    Policy
+	observer  Observer
+	next      http.RoundTripper
+}
+
+// newRoutingTransport builds a routingTransport from the options
+// collected via WithEndpoints/WithRoutingPolicy/WithRoutingObserver. next
+// is the RoundTripper used to actually perform each attempt (typically
+// http.DefaultTransport or a caller-supplied one).
+func newRoutingTransport(endpoints []EndpointSpec, policy Policy, observer Observer, next http.RoundTripper) *routingTransport {
+	if observer == nil {
+		observer = noopObserver{}
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &routingTransport{endpoints: endpoints, policy: policy, observer: observer, next: next}
+}
+
+// orderedEndpoints returns the endpoints eligible for this transport's
+// policy, in the order they should be attempted.
+func (t *routingTransport) orderedEndpoints() []EndpointSpec {
+	eligible := make([]EndpointSpec, 0, len(t.endpoints))
+	for _, e := range t.endpoints {
+		if t.policy.Kind == PolicyRequireParameters && !e.supports(t.policy.RequiredParameters) {
+			continue
+		}
+		eligible = append(eligible, e)
+	}
+
+	if t.policy.Kind == PolicyCheapestFirst {
+		sorted := make([]EndpointSpec, len(eligible))
+		copy(sorted, eligible)
+		for i := 1; i < len(sorted); i++ {
+			for j := i; j > 0 && sorted[j].CostWeight < sorted[j-1].CostWeight; j-- {
+				sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+			}
+		}
+		return sorted
+	}
+	return eligible
+}
+
+// isRetryableStatus reports whether status warrants trying the next
+// endpoint rather than returning the response to the caller.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// backoffWithJitter returns the delay before retry number attempt
+// (0 for the first retry), exponential in attempt with full jitter.
+func backoffWithJitter(attempt int) time.Duration {
+	const base = 200 * time.Millisecond
+	const max = 10 * time.Second
+	d := base * time.Duration(math.Pow(2, float64(attempt)))
+	if d > max {
+		d = max
+	}
+	return time.Duration(rand.Int63n(int64(d))) //nolint:gosec
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *routingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoints := t.orderedEndpoints()
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpointsAvailable
+	}
+
+	maxAttempts := t.policy.MaxAttempts
+	if maxAttempts <= 0 || maxAttempts > len(endpoints) {
+		maxAttempts = len(endpoints)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(backoffWithJitter(attempt - 1)):
+			}
+		}
+
+		endpoint := endpoints[attempt]
+		attemptReq := req.Clone(req.Context())
+		attemptReq.URL.Scheme, attemptReq.URL.Host = splitBaseURL(endpoint.BaseURL)
+
+		// req.Clone is a shallow copy: it shares the original Body reader,
+		// which the previous attempt has already drained. Every
+		// maritacaclient request has a body (it's always a JSON POST), so
+		// without a fresh reader from GetBody, attempt N>0 sends an empty
+		// body against the declared Content-Length and fails locally
+		// before reaching the endpoint.
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				lastErr = fmt.Errorf("maritaca: cannot retry request with a body that does not support GetBody")
+				break
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				lastErr = fmt.Errorf("maritaca: rewinding request body for retry: %w", err)
+				break
+			}
+			attemptReq.Body = body
+		}
+
+		start := time.Now()
+		resp, err := t.next.RoundTrip(attemptReq)
+		duration := time.Since(start)
+
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		t.observer.OnAttempt(AttemptResult{Endpoint: endpoint, Attempt: attempt, StatusCode: status, Err: err, Duration: duration})
+
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if isRetryableStatus(resp.StatusCode) {
+			lastErr = errUnexpectedStatus(resp.StatusCode)
+			resp.Body.Close()
+			continue
+		}
+		return resp, nil
+	}
+
+	t.observer.OnExhausted(lastErr)
+	if lastErr == nil {
+		lastErr = ErrNoEndpointsAvailable
+	}
+	return nil, lastErr
+}
+
+// splitBaseURL splits an endpoint's BaseURL into the scheme and host
+// components used to redirect an outgoing request.
+func splitBaseURL(rawURL string) (scheme, host string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", rawURL
+	}
+	return u.Scheme, u.Host
+}
+
+type statusError int
+
+func (e statusError) Error() string {
+	return "maritaca: endpoint returned retryable status " + http.StatusText(int(e))
+}
+
+func errUnexpectedStatus(status int) error {
+	return statusError(status)
+}
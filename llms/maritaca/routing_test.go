@@ -0,0 +1,136 @@
+package maritaca
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoutingTransportFallsBackOnRetryableStatus(t *testing.T) {
+	t.Parallel()
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer bad.Close()
+
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer good.Close()
+
+	endpoints := []EndpointSpec{
+		{Name: "bad", BaseURL: bad.URL},
+		{Name: "good", BaseURL: good.URL},
+	}
+
+	transport := newRoutingTransport(endpoints, Policy{Kind: PolicyOrderedFallback}, nil, http.DefaultTransport)
+
+	req, err := http.NewRequest(http.MethodGet, bad.URL, nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected fallback to the good endpoint, got status %d", resp.StatusCode)
+	}
+}
+
+func TestRoutingTransportRewindsBodyOnRetry(t *testing.T) {
+	t.Parallel()
+
+	const payload = `{"model":"sabia-3","messages":["hi"]}`
+
+	var badAttempts int
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		badAttempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer bad.Close()
+
+	var goodBody string
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading body on good endpoint: %v", err)
+		}
+		goodBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer good.Close()
+
+	endpoints := []EndpointSpec{
+		{Name: "bad", BaseURL: bad.URL},
+		{Name: "good", BaseURL: good.URL},
+	}
+
+	transport := newRoutingTransport(endpoints, Policy{Kind: PolicyOrderedFallback}, nil, http.DefaultTransport)
+
+	req, err := http.NewRequest(http.MethodPost, bad.URL, bytes.NewReader([]byte(payload)))
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	req.ContentLength = int64(len(payload))
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected fallback to the good endpoint, got status %d", resp.StatusCode)
+	}
+	if badAttempts != 1 {
+		t.Errorf("expected exactly one attempt against the bad endpoint, got %d", badAttempts)
+	}
+	if goodBody != payload {
+		t.Errorf("expected the full body to reach the fallback endpoint, got %q", goodBody)
+	}
+}
+
+func TestOrderedEndpointsCheapestFirst(t *testing.T) {
+	t.Parallel()
+
+	transport := &routingTransport{
+		endpoints: []EndpointSpec{
+			{Name: "expensive", CostWeight: 3},
+			{Name: "cheap", CostWeight: 1},
+			{Name: "mid", CostWeight: 2},
+		},
+		policy: Policy{Kind: PolicyCheapestFirst},
+	}
+
+	got := transport.orderedEndpoints()
+	want := []string{"cheap", "mid", "expensive"}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Errorf("orderedEndpoints()[%d] = %q, want %q", i, got[i].Name, name)
+		}
+	}
+}
+
+func TestOrderedEndpointsRequireParametersSkipsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	transport := &routingTransport{
+		endpoints: []EndpointSpec{
+			{Name: "basic", SupportedParameters: []string{"temperature"}},
+			{Name: "full", SupportedParameters: []string{"temperature", "logit_bias"}},
+		},
+		policy: Policy{Kind: PolicyRequireParameters, RequiredParameters: []string{"logit_bias"}},
+	}
+
+	got := transport.orderedEndpoints()
+	if len(got) != 1 || got[0].Name != "full" {
+		t.Errorf("expected only the endpoint supporting logit_bias, got %+v", got)
+	}
+}
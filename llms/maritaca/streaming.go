@@ -0,0 +1,52 @@
+package maritaca
+
+import (
+	"context"
+
+	"github.com/silverfox07/langchaingo/llms"
+)
+
+// StreamChunk carries a single incremental update from a streaming
+// generation request, mirroring the chunk shape used by OpenAISwift and
+// the OpenAI Chat API.
+type StreamChunk struct {
+	// Content is the delta text produced since the previous chunk.
+	Content string
+	// TokenCount is the number of tokens represented by Content.
+	TokenCount int
+	// FinishReason is set on the final chunk of the stream (e.g. "stop",
+	// "length"), and empty otherwise.
+	FinishReason string
+	// LogProbs holds the per-token log probabilities for Content, when
+	// the backend provides them.
+	LogProbs map[string]float64
+}
+
+// StreamingFunc is called once per chunk received while streaming a
+// generation request. Returning an error aborts the underlying HTTP
+// request via context cancellation.
+type StreamingFunc func(ctx context.Context, chunk StreamChunk) error
+
+// WithStreamingFunc sets the callback used to consume generated tokens
+// incrementally. It implies WithStream(true). If no callback is set but
+// streaming is enabled, chunks are accumulated internally and returned
+// as a single response, as before.
+func WithStreamingFunc(fn StreamingFunc) Option {
+	return func(opts *options) {
+		opts.maritacaOptions.Stream = true
+		opts.streamingFunc = fn
+	}
+}
+
+// streamingFunc resolves the callback to use for this call, preferring
+// the per-call llms.WithStreamingFunc (adapted to StreamChunk) over the
+// LLM-level WithStreamingFunc, matching how other langchaingo providers
+// let call-time options override construction-time ones.
+func (o *LLM) streamingFunc(callOpts llms.CallOptions) StreamingFunc {
+	if callOpts.StreamingFunc != nil {
+		return func(ctx context.Context, chunk StreamChunk) error {
+			return callOpts.StreamingFunc(ctx, []byte(chunk.Content))
+		}
+	}
+	return o.options.streamingFunc
+}